@@ -0,0 +1,124 @@
+package participle
+
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// CaptureComments strips tokens of the given symbol (eg. "Comment") from the token
+// stream and buffers them; the next struct field tagged `doc:""` receives them,
+// joined with newlines, into a string or []string field. The buffer is flushed on any
+// non-comment token, so trailing comments never leak onto an unrelated following node.
+func CaptureComments(symbol string) Option {
+	return func(p *Parser) error {
+		p.commentSymbol = symbol
+		return nil
+	}
+}
+
+// commentLexerDef wraps a lexer.Definition, hiding tokens of the configured comment
+// symbol from the grammar while buffering them for the next doc:"" capture.
+type commentLexerDef struct {
+	lexer.Definition
+	symbol string
+}
+
+func (c *commentLexerDef) Lex(r io.Reader) lexer.Lexer {
+	return &commentLexer{Lexer: c.Definition.Lex(r), symbolRune: c.Definition.Symbols()[c.symbol]}
+}
+
+// commentLexer filters comment tokens out of the stream, accumulating each
+// contiguous run into a queue so attachDocComments can later hand them out, in order,
+// to the doc:"" fields that were being captured as the tokens went by.
+type commentLexer struct {
+	lexer.Lexer
+	symbolRune rune
+	buf        []string
+	runs       []string
+}
+
+func (c *commentLexer) Next() (lexer.Token, error) {
+	for {
+		token, err := c.Lexer.Next()
+		if err != nil {
+			return token, err
+		}
+		if token.Type != c.symbolRune {
+			c.flush()
+			return token, nil
+		}
+		c.buf = append(c.buf, strings.TrimRight(token.Value, "\n"))
+	}
+}
+
+// flush ends the current comment run, if any, queuing it for the next call to next().
+// It is called on every non-comment token, so trailing comments never leak onto an
+// unrelated following node.
+func (c *commentLexer) flush() {
+	if len(c.buf) == 0 {
+		return
+	}
+	c.runs = append(c.runs, strings.Join(c.buf, "\n"))
+	c.buf = nil
+}
+
+// next dequeues the next buffered comment run, or "" if none is pending.
+func (c *commentLexer) next() string {
+	if len(c.runs) == 0 {
+		return ""
+	}
+	doc := c.runs[0]
+	c.runs = c.runs[1:]
+	return doc
+}
+
+// attachDocComments walks v's struct fields in declaration order - the same order
+// their tokens were consumed in - handing each field tagged `doc:""` the next queued
+// comment run from lex, into a string or []string field. It descends into the pointer
+// held by an interface field, such as a Union() member.
+func attachDocComments(v reflect.Value, lex *commentLexer) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		if _, ok := field.Tag.Lookup("doc"); ok {
+			doc := lex.next()
+			switch {
+			case fv.Kind() == reflect.String:
+				fv.SetString(doc)
+			case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String:
+				if doc != "" {
+					fv.Set(reflect.ValueOf(strings.Split(doc, "\n")))
+				}
+			}
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.Struct, reflect.Ptr:
+			attachDocComments(fv, lex)
+		case reflect.Interface:
+			if !fv.IsNil() && fv.Elem().Kind() == reflect.Ptr {
+				attachDocComments(fv.Elem(), lex)
+			}
+		case reflect.Slice:
+			for j := 0; j < fv.Len(); j++ {
+				attachDocComments(fv.Index(j), lex)
+			}
+		}
+	}
+}
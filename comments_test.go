@@ -0,0 +1,50 @@
+package participle
+
+import (
+	"reflect"
+	"testing"
+)
+
+type commentTestDoc struct {
+	Doc string `doc:""`
+}
+
+type commentTestMultiDoc struct {
+	Docs []string `doc:""`
+}
+
+func TestCommentLexerFlushesRunsInOrder(t *testing.T) {
+	c := &commentLexer{}
+	c.buf = []string{"a"}
+	c.flush()
+	c.buf = []string{"b", "c"}
+	c.flush()
+
+	if got := c.next(); got != "a" {
+		t.Fatalf("expected first run %q, got %q", "a", got)
+	}
+	if got := c.next(); got != "b\nc" {
+		t.Fatalf("expected joined second run %q, got %q", "b\nc", got)
+	}
+	if got := c.next(); got != "" {
+		t.Fatalf("expected empty run once queue drained, got %q", got)
+	}
+}
+
+func TestAttachDocCommentsSetsStringField(t *testing.T) {
+	c := &commentLexer{runs: []string{"hello\nworld"}}
+	v := &commentTestDoc{}
+	attachDocComments(reflect.ValueOf(v), c)
+	if v.Doc != "hello\nworld" {
+		t.Fatalf("expected Doc to be set from queued run, got %q", v.Doc)
+	}
+}
+
+func TestAttachDocCommentsSplitsIntoSlice(t *testing.T) {
+	c := &commentLexer{runs: []string{"one\ntwo"}}
+	v := &commentTestMultiDoc{}
+	attachDocComments(reflect.ValueOf(v), c)
+	if len(v.Docs) != 2 || v.Docs[0] != "one" || v.Docs[1] != "two" {
+		t.Fatalf("expected Docs to be split by line, got %v", v.Docs)
+	}
+}
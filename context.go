@@ -0,0 +1,96 @@
+package participle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// generatorContext carries the state needed while walking a grammar's reflect.Type
+// tree and turning it into a node tree: the lexer.Definition (for resolving symbol
+// names used in `@Symbol` tags) and the prebuilt union alternations (for resolving
+// interface-typed fields registered via Union()).
+type generatorContext struct {
+	lex    lexer.Definition
+	unions map[reflect.Type]*unionNode
+}
+
+func newGeneratorContext(lex lexer.Definition) *generatorContext {
+	return &generatorContext{lex: lex}
+}
+
+// parseType builds the node for t, which may be a struct, a pointer to one, or an
+// interface registered via Union().
+func (c *generatorContext) parseType(t reflect.Type) (node, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		u, ok := c.unions[t]
+		if !ok {
+			return nil, fmt.Errorf("participle: no union registered for interface %s, use participle.Union()", t)
+		}
+		return u, nil
+	case reflect.Struct:
+		return c.parseStruct(t)
+	default:
+		return nil, fmt.Errorf("participle: cannot build grammar from %s, expected a struct or registered interface", t)
+	}
+}
+
+// parseStruct builds a *strctNode from every exported field of t that carries a
+// non-empty `parser:""` tag.
+func (c *generatorContext) parseStruct(t reflect.Type) (node, error) {
+	s := &strctNode{typ: t}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("parser")
+		if tag == "" {
+			continue
+		}
+		fieldNode, err := c.parseField(field, tag)
+		if err != nil {
+			return nil, fmt.Errorf("participle: %s.%s: %w", t, field.Name, err)
+		}
+		s.fields = append(s.fields, fieldNode)
+	}
+	if len(s.fields) == 0 {
+		return nil, fmt.Errorf("participle: %s has no fields with a parser tag", t)
+	}
+	return s, nil
+}
+
+// parseField builds the node for a single struct field from its parser tag, which is
+// either "@@" (a reference to another grammar type, field.Type is recursed into via
+// parseType) or "@Symbol" (a capture of a single token of the named lexer symbol).
+func (c *generatorContext) parseField(field reflect.StructField, tag string) (node, error) {
+	switch {
+	case tag == "@@":
+		target, err := c.parseType(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &referenceNode{fieldIndex: field.Index, fieldName: field.Name, fieldType: field.Type, target: target}, nil
+	case strings.HasPrefix(tag, "@"):
+		symbolName := tag[1:]
+		symbol, ok := c.lex.Symbols()[symbolName]
+		if !ok {
+			return nil, fmt.Errorf("unknown lexer symbol %q", symbolName)
+		}
+		return &captureNode{fieldIndex: field.Index, fieldName: field.Name, symbol: symbol, symbolName: symbolName}, nil
+	default:
+		return nil, fmt.Errorf("unsupported parser tag %q", tag)
+	}
+}
+
+// applyLookahead would rewrite ambiguous alternations to use unlimited lookahead; not
+// yet implemented (see the TODO on UseLookahead's use in Build).
+func applyLookahead(n node, seen map[node]bool) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package participle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// TestParseTypeSubstitutesUnionForInterfaceField exercises the generator context
+// directly - the layer Build in parser.go delegates to - to confirm that an
+// interface type registered via buildUnionNodes is actually looked up and substituted
+// by parseType, rather than only being reachable through a full Build()+Parse() round
+// trip.
+func TestParseTypeSubstitutesUnionForInterfaceField(t *testing.T) {
+	context := newGeneratorContext(lexer.TextScannerLexer)
+	unions := map[reflect.Type][]reflect.Type{
+		reflect.TypeOf((*unionTestExpr)(nil)).Elem(): {reflect.TypeOf(unionTestLit{})},
+	}
+	var err error
+	if context.unions, err = buildUnionNodes(context, unions); err != nil {
+		t.Fatalf("buildUnionNodes: %s", err)
+	}
+	ifaceType := reflect.TypeOf((*unionTestExpr)(nil)).Elem()
+	n, err := context.parseType(ifaceType)
+	if err != nil {
+		t.Fatalf("parseType: %s", err)
+	}
+	u, ok := n.(*unionNode)
+	if !ok {
+		t.Fatalf("expected parseType to substitute the built *unionNode, got %T", n)
+	}
+	if u != context.unions[ifaceType] {
+		t.Fatalf("expected the exact *unionNode stored in context.unions, got a different instance")
+	}
+}
+
+// TestParseTypeRejectsUnregisteredInterface confirms parseType's other branch: an
+// interface with no corresponding entry in context.unions is a build error, not a
+// silent pass-through.
+func TestParseTypeRejectsUnregisteredInterface(t *testing.T) {
+	context := newGeneratorContext(lexer.TextScannerLexer)
+	_, err := context.parseType(reflect.TypeOf((*unionTestExpr)(nil)).Elem())
+	if err == nil {
+		t.Fatal("expected an error for an interface with no registered union")
+	}
+}
@@ -0,0 +1,141 @@
+package participle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EBNF renders the grammar as canonical EBNF, one production per struct type
+// referenced, labelled by type name so it can be read independently of the Go source -
+// unlike Parser.String(), which dumps the root node as a single unlabelled fragment.
+func (p *Parser) EBNF() string {
+	productions := map[string]string{}
+	var order []string
+	var visit func(n node)
+	visit = func(n node) {
+		switch n := n.(type) {
+		case *strctNode:
+			name := n.typ.Name()
+			if _, ok := productions[name]; !ok {
+				productions[name] = dumpNode(n)
+				order = append(order, name)
+				for _, f := range n.fields {
+					visit(f)
+				}
+			}
+		case *referenceNode:
+			visit(n.target)
+		case *unionNode:
+			for _, m := range n.members {
+				visit(m)
+			}
+		}
+	}
+	visit(p.root)
+	lines := make([]string, 0, len(order))
+	for _, name := range order {
+		lines = append(lines, fmt.Sprintf("%s = %s ;", name, productions[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NodeKind identifies the shape of a GrammarNode in a GrammarDescriptor.
+type NodeKind string
+
+// The kinds of node a GrammarDescriptor can describe, mirroring the internal node
+// types participle builds from a grammar.
+const (
+	KindSequence    NodeKind = "sequence"
+	KindAlternation NodeKind = "alternation"
+	KindCapture     NodeKind = "capture"
+	KindReference   NodeKind = "reference"
+	KindTerminal    NodeKind = "terminal"
+	KindRepetition  NodeKind = "repetition"
+	KindOptional    NodeKind = "optional"
+)
+
+// GrammarNode is the serializable form of one node in the grammar tree.
+type GrammarNode struct {
+	Kind  NodeKind      `json:"kind"`
+	Field string        `json:"field,omitempty"` // Go struct field this node populates, if any.
+	Type  string        `json:"type,omitempty"`  // Go type name for sequence/alternation/reference nodes; lexer symbol name for capture nodes.
+	Value string        `json:"value,omitempty"` // Literal text, for terminal nodes.
+	Nodes []GrammarNode `json:"nodes,omitempty"` // Children, for sequence/alternation/reference/repetition/optional.
+}
+
+// GrammarDescriptor is a serializable description of a built grammar, analogous to a
+// Protobuf FileDescriptor: it lets downstream tools generate railroad diagrams,
+// validate grammar diffs across versions, or drive editor tooling without importing
+// the generator internals.
+type GrammarDescriptor struct {
+	Type string      `json:"type"` // Go type name of the grammar root.
+	Root GrammarNode `json:"root"`
+}
+
+// Descriptor returns a serializable description of p's grammar.
+func (p *Parser) Descriptor() *GrammarDescriptor {
+	return &GrammarDescriptor{
+		Type: p.typ.String(),
+		Root: describeNode(p.root),
+	}
+}
+
+// describeNode converts an internal node into its serializable GrammarNode form,
+// recursing into children so the descriptor mirrors the actual node tree rather than
+// flattening it. Node kinds without a dedicated case here - repetition and optional,
+// which this package doesn't yet build - fall back to a terminal carrying their EBNF
+// text, so the descriptor still round-trips into something renderable once those
+// cases exist.
+func describeNode(n node) GrammarNode {
+	switch n := n.(type) {
+	case *unionNode:
+		nodes := make([]GrammarNode, 0, len(n.members))
+		for _, member := range n.members {
+			nodes = append(nodes, describeNode(member))
+		}
+		return GrammarNode{Kind: KindAlternation, Type: n.iface.String(), Nodes: nodes}
+	case *strctNode:
+		nodes := make([]GrammarNode, 0, len(n.fields))
+		for _, field := range n.fields {
+			nodes = append(nodes, describeNode(field))
+		}
+		return GrammarNode{Kind: KindSequence, Type: n.typ.String(), Nodes: nodes}
+	case *captureNode:
+		return GrammarNode{Kind: KindCapture, Field: n.fieldName, Type: n.symbolName}
+	case *referenceNode:
+		return GrammarNode{Kind: KindReference, Field: n.fieldName, Type: n.fieldType.String(), Nodes: []GrammarNode{describeNode(n.target)}}
+	default:
+		return GrammarNode{Kind: KindTerminal, Value: dumpNode(n)}
+	}
+}
+
+// LoadDescriptor reconstructs a *Parser from a GrammarDescriptor previously produced
+// by Descriptor(), type-checking it against grammar, the same Go grammar value that
+// would be passed to Build: grammar is built normally, and the result must describe
+// exactly the same tree as d, not just share a root type name. This lets a tool ship
+// (or diff) just the descriptor while still parsing with the real, reflection-backed
+// grammar.
+func LoadDescriptor(d *GrammarDescriptor, grammar interface{}, options ...Option) (*Parser, error) {
+	p, err := Build(grammar, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := descriptorMatches(d, p.Descriptor()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// descriptorMatches returns an error describing the first structural difference
+// between a descriptor that was loaded (want) and one freshly built from the grammar
+// Go type (got).
+func descriptorMatches(want, got *GrammarDescriptor) error {
+	if want.Type != got.Type {
+		return fmt.Errorf("participle: descriptor is for type %s, not %s", want.Type, got.Type)
+	}
+	if !reflect.DeepEqual(want.Root, got.Root) {
+		return fmt.Errorf("participle: descriptor does not match the grammar structure built from %s", got.Type)
+	}
+	return nil
+}
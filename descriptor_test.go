@@ -0,0 +1,113 @@
+package participle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribeNodeRecursesIntoUnionMembers(t *testing.T) {
+	u := &unionNode{
+		iface:   reflect.TypeOf((*unionTestExpr)(nil)).Elem(),
+		members: []node{failNode{}, failNode{}},
+	}
+	got := describeNode(u)
+	if got.Kind != KindAlternation {
+		t.Fatalf("expected KindAlternation, got %s", got.Kind)
+	}
+	if len(got.Nodes) != 2 {
+		t.Fatalf("expected 2 child nodes, got %d", len(got.Nodes))
+	}
+	for _, child := range got.Nodes {
+		if child.Kind != KindTerminal {
+			t.Fatalf("expected child node to fall back to KindTerminal, got %s", child.Kind)
+		}
+	}
+}
+
+func TestDescribeNodeCapturesEachConcreteKindInIsolation(t *testing.T) {
+	capture := &captureNode{fieldName: "Value", symbol: 'I', symbolName: "Ident"}
+	if got := describeNode(capture); got.Kind != KindCapture || got.Field != "Value" || got.Type != "Ident" {
+		t.Fatalf("expected a KindCapture node labelled by field/symbol, got %+v", got)
+	}
+
+	strct := &strctNode{typ: reflect.TypeOf(unionTestLit{}), fields: []node{capture}}
+	got := describeNode(strct)
+	if got.Kind != KindSequence || got.Type != "participle.unionTestLit" {
+		t.Fatalf("expected a KindSequence node labelled by struct type, got %+v", got)
+	}
+	if len(got.Nodes) != 1 || got.Nodes[0].Kind != KindCapture {
+		t.Fatalf("expected KindSequence to recurse into its field, got %+v", got.Nodes)
+	}
+
+	ref := &referenceNode{fieldName: "Expr", fieldType: reflect.TypeOf((*unionTestExpr)(nil)).Elem(), target: strct}
+	got = describeNode(ref)
+	if got.Kind != KindReference || got.Field != "Expr" {
+		t.Fatalf("expected a KindReference node labelled by field, got %+v", got)
+	}
+	if len(got.Nodes) != 1 || got.Nodes[0].Kind != KindSequence {
+		t.Fatalf("expected KindReference to recurse into its target, got %+v", got.Nodes)
+	}
+}
+
+func TestDescribeNodeDescribesRealGrammarTree(t *testing.T) {
+	parser, err := Build(&unionTestWrapper{}, Union((*unionTestExpr)(nil), &unionTestLit{}))
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	d := parser.Descriptor()
+	if d.Root.Kind != KindSequence {
+		t.Fatalf("expected root KindSequence, got %s", d.Root.Kind)
+	}
+	if len(d.Root.Nodes) != 1 || d.Root.Nodes[0].Kind != KindReference {
+		t.Fatalf("expected a single KindReference child, got %+v", d.Root.Nodes)
+	}
+	alt := d.Root.Nodes[0].Nodes
+	if len(alt) != 1 || alt[0].Kind != KindAlternation {
+		t.Fatalf("expected reference to recurse into KindAlternation, got %+v", alt)
+	}
+	lit := alt[0].Nodes
+	if len(lit) != 1 || lit[0].Kind != KindSequence {
+		t.Fatalf("expected union member to be KindSequence, got %+v", lit)
+	}
+	if len(lit[0].Nodes) != 1 || lit[0].Nodes[0].Kind != KindCapture {
+		t.Fatalf("expected unionTestLit to contain a KindCapture field, got %+v", lit[0].Nodes)
+	}
+}
+
+func TestEBNFLabelsProductionsByType(t *testing.T) {
+	parser, err := Build(&unionTestWrapper{}, Union((*unionTestExpr)(nil), &unionTestLit{}))
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	ebnf := parser.EBNF()
+	if !strings.Contains(ebnf, "unionTestWrapper =") {
+		t.Fatalf("expected EBNF to contain a labelled unionTestWrapper production, got %q", ebnf)
+	}
+	if !strings.Contains(ebnf, "unionTestLit =") {
+		t.Fatalf("expected EBNF to contain a labelled unionTestLit production, got %q", ebnf)
+	}
+}
+
+func TestDescriptorMatchesDetectsTypeMismatch(t *testing.T) {
+	want := &GrammarDescriptor{Type: "foo.A"}
+	got := &GrammarDescriptor{Type: "foo.B"}
+	if err := descriptorMatches(want, got); err == nil {
+		t.Fatal("expected an error for mismatched grammar types")
+	}
+}
+
+func TestDescriptorMatchesDetectsStructuralMismatch(t *testing.T) {
+	want := &GrammarDescriptor{Type: "foo.A", Root: GrammarNode{Kind: KindAlternation}}
+	got := &GrammarDescriptor{Type: "foo.A", Root: GrammarNode{Kind: KindTerminal}}
+	if err := descriptorMatches(want, got); err == nil {
+		t.Fatal("expected an error for mismatched grammar structure")
+	}
+}
+
+func TestDescriptorMatchesAcceptsIdenticalDescriptors(t *testing.T) {
+	d := &GrammarDescriptor{Type: "foo.A", Root: GrammarNode{Kind: KindTerminal, Value: "A"}}
+	if err := descriptorMatches(d, d); err != nil {
+		t.Fatalf("expected identical descriptors to match, got %s", err)
+	}
+}
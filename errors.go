@@ -0,0 +1,169 @@
+package participle
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// ErrorList accumulates syntax errors recorded while recovering with Recover(), sorted
+// by position. It implements error so it can be returned directly.
+type ErrorList []error
+
+func (e ErrorList) Error() string {
+	parts := make([]string, len(e))
+	for i, err := range e {
+		parts[i] = err.Error()
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Recover enables error recovery in ParseAll: instead of aborting at the first syntax
+// error, up to max errors are collected before giving up.
+func Recover(max int) Option {
+	return func(p *Parser) error {
+		p.recoverMax = max
+		return nil
+	}
+}
+
+// Sync configures the terminals used to resynchronise after a recovered error: when a
+// production fails, tokens are skipped up to and including the next one of these (or
+// up to EOF), and parsing resumes after it. If not provided, Recover() defaults to whichever of ";"
+// and "}" are declared in the lexer's symbols.
+func Sync(tokens ...string) Option {
+	return func(p *Parser) error {
+		p.syncTokens = tokens
+		return nil
+	}
+}
+
+// ParseAll parses as many top-level productions of r into the slice v as it can.
+// Without Recover(), it stops at the first syntax error and returns it directly, the
+// same as Parse. With Recover(max) in effect, it instead synchronises on a Sync token
+// and keeps going, collecting up to max errors and returning them sorted by position as
+// an ErrorList, or the error alone when only one was collected.
+//
+// v must be a pointer to a slice; each successfully parsed production is appended to
+// it, regardless of whether recovery is enabled.
+func (p *Parser) ParseAll(r io.Reader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return lexer.Errorf(lexer.Position{}, "ParseAll: target must be a pointer to a slice")
+	}
+	elemType := rv.Elem().Type().Elem()
+
+	recovering := p.recoverMax > 0
+	max := p.recoverMax
+	if !recovering {
+		max = 1
+	}
+
+	lex := lexer.Upgrade(p.lex.Lex(r))
+	caseInsensitive := map[rune]bool{}
+	for sym, rn := range p.lex.Symbols() {
+		if p.caseInsensitive[sym] {
+			caseInsensitive[rn] = true
+		}
+	}
+	sync := p.syncSymbols()
+
+	var errs ErrorList
+	for {
+		token, err := lex.Peek(0)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if token.EOF() {
+			break
+		}
+		elem := reflect.New(elemType)
+		ctx := parseContext{PeekingLexer: lex, caseInsensitive: caseInsensitive}
+		pv, err := p.root.Parse(ctx, elem.Elem())
+		if err == nil && len(pv) > 0 {
+			after, peekErr := lex.Peek(0)
+			if peekErr != nil {
+				errs = append(errs, peekErr)
+				break
+			}
+			if after.Pos.Offset == token.Pos.Offset && !after.EOF() {
+				errs = append(errs, lexer.Errorf(after.Pos, "grammar matched without consuming any input, refusing to loop forever"))
+				break
+			}
+			rv.Elem().Set(reflect.Append(rv.Elem(), reflect.Indirect(pv[0])))
+			continue
+		}
+		if err == nil {
+			err = lexer.Errorf(token.Pos, "invalid syntax")
+		}
+		errs = append(errs, err)
+		if !recovering || len(errs) >= max {
+			break
+		}
+		p.resync(lex, sync)
+	}
+	sortErrorsByPosition(errs)
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// errorPosition is implemented by errors that know where in the source they occurred,
+// such as the ones lexer.Errorf produces.
+type errorPosition interface {
+	Position() lexer.Position
+}
+
+// sortErrorsByPosition sorts errs by source offset. Errors are already recorded in
+// that order since ParseAll scans left to right, but sorting explicitly keeps the
+// promise true regardless of how errors are accumulated; errors without a known
+// position keep their relative place.
+func sortErrorsByPosition(errs ErrorList) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		pi, oki := errs[i].(errorPosition)
+		pj, okj := errs[j].(errorPosition)
+		if !oki || !okj {
+			return false
+		}
+		return pi.Position().Offset < pj.Position().Offset
+	})
+}
+
+// resync skips tokens up to and including the first one in sync, or up to EOF.
+func (p *Parser) resync(lex lexer.PeekingLexer, sync map[rune]bool) {
+	for {
+		token, err := lex.Peek(0)
+		if err != nil || token.EOF() {
+			return
+		}
+		if sync[token.Type] {
+			_, _ = lex.Next()
+			return
+		}
+		_, _ = lex.Next()
+	}
+}
+
+func (p *Parser) syncSymbols() map[rune]bool {
+	out := map[rune]bool{}
+	names := p.syncTokens
+	if len(names) == 0 {
+		names = []string{";", "}"}
+	}
+	symbols := p.lex.Symbols()
+	for _, name := range names {
+		if rn, ok := symbols[name]; ok {
+			out[rn] = true
+		}
+	}
+	return out
+}
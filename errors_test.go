@@ -0,0 +1,130 @@
+package participle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+type posError struct {
+	msg string
+	pos lexer.Position
+}
+
+func (e posError) Error() string            { return e.msg }
+func (e posError) Position() lexer.Position { return e.pos }
+
+func TestSortErrorsByPositionOrdersByOffset(t *testing.T) {
+	errs := ErrorList{
+		posError{msg: "second", pos: lexer.Position{Offset: 10}},
+		posError{msg: "first", pos: lexer.Position{Offset: 2}},
+	}
+	sortErrorsByPosition(errs)
+	if errs[0].Error() != "first" || errs[1].Error() != "second" {
+		t.Fatalf("expected errors sorted by offset, got %v", errs)
+	}
+}
+
+func TestSortErrorsByPositionLeavesUnpositionedErrorsInPlace(t *testing.T) {
+	errs := ErrorList{
+		posError{msg: "a", pos: lexer.Position{Offset: 5}},
+		errPlain{"b"},
+	}
+	sortErrorsByPosition(errs)
+	if errs[0].Error() != "a" || errs[1].Error() != "b" {
+		t.Fatalf("expected order preserved for unpositioned error, got %v", errs)
+	}
+}
+
+type errPlain struct{ msg string }
+
+func (e errPlain) Error() string { return e.msg }
+
+func TestErrorListJoinsMessages(t *testing.T) {
+	errs := ErrorList{errPlain{"a"}, errPlain{"b"}}
+	if got, want := errs.Error(), "a\nb"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+type errorsTestWord struct {
+	Value string `parser:"@Ident"`
+}
+
+func TestParseAllCollectsEveryProduction(t *testing.T) {
+	parser, err := Build(&errorsTestWord{})
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	var words []errorsTestWord
+	if err := parser.ParseAll(strings.NewReader("one two three"), &words); err != nil {
+		t.Fatalf("ParseAll: %s", err)
+	}
+	if len(words) != 3 || words[0].Value != "one" || words[1].Value != "two" || words[2].Value != "three" {
+		t.Fatalf("expected [one two three], got %+v", words)
+	}
+}
+
+func TestParseAllReturnsNoProductionsForEmptyInput(t *testing.T) {
+	parser, err := Build(&errorsTestWord{})
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	var words []errorsTestWord
+	if err := parser.ParseAll(strings.NewReader(""), &words); err != nil {
+		t.Fatalf("ParseAll on empty input: %s", err)
+	}
+	if len(words) != 0 {
+		t.Fatalf("expected no words, got %+v", words)
+	}
+}
+
+// zeroLenMatchNode reports success (a non-nil return) while returning zero values,
+// reproducing the exact shape Parse's "if len(pv) > 0" guard exists for: a non-nil but
+// zero-length pv.
+type zeroLenMatchNode struct{}
+
+func (zeroLenMatchNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	return []reflect.Value{}, nil
+}
+
+func TestParseAllDoesNotPanicOnNonNilZeroLengthPv(t *testing.T) {
+	parser, err := Build(&errorsTestWord{})
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	parser.root = zeroLenMatchNode{}
+	var words []errorsTestWord
+	// A non-nil, zero-length pv must not be indexed as pv[0]; ParseAll should treat it
+	// as "didn't actually produce a value" rather than panicking.
+	err = parser.ParseAll(strings.NewReader("one"), &words)
+	if err == nil {
+		t.Fatal("expected an error since the fake root never consumes input or appends a value")
+	}
+	if len(words) != 0 {
+		t.Fatalf("expected no words appended, got %+v", words)
+	}
+}
+
+func TestParseAllRejectsZeroProgressProduction(t *testing.T) {
+	parser, err := Build(&errorsTestWord{})
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	parser.root = zeroProgressNode{}
+	var words []errorsTestWord
+	err = parser.ParseAll(strings.NewReader("one"), &words)
+	if err == nil {
+		t.Fatal("expected an error rather than looping forever on a zero-progress match")
+	}
+}
+
+// zeroProgressNode matches successfully without consuming any tokens, which would spin
+// ParseAll's loop forever without a forward-progress check between iterations.
+type zeroProgressNode struct{}
+
+func (zeroProgressNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	return []reflect.Value{parent}, nil
+}
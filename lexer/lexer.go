@@ -0,0 +1,140 @@
+// Package lexer tokenises input for participle grammars.
+package lexer
+
+import (
+	"fmt"
+	"io"
+)
+
+// EOF is the token type returned once a Lexer has exhausted its input.
+const EOF rune = -1
+
+// Position describes where in the source a Token begins.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (p Position) String() string {
+	filename := p.Filename
+	if filename == "" {
+		filename = "<source>"
+	}
+	return fmt.Sprintf("%s:%d:%d", filename, p.Line, p.Column)
+}
+
+// Token is a single lexeme. Type identifies which symbol of the Definition that
+// produced it matched (see Definition.Symbols); Value is the matched text.
+type Token struct {
+	Type  rune
+	Value string
+	Pos   Position
+}
+
+// EOF reports whether this token is the end-of-input sentinel.
+func (t Token) EOF() bool { return t.Type == EOF }
+
+func (t Token) String() string { return t.Value }
+
+// Lexer tokenises a single pass over some input, one Token per call to Next.
+type Lexer interface {
+	Next() (Token, error)
+}
+
+// Definition constructs a Lexer over some input, and describes the token types that
+// Lexer can produce.
+type Definition interface {
+	Lex(r io.Reader) Lexer
+	Symbols() map[string]rune
+}
+
+// Error is a lex or parse error associated with a position in the source.
+type Error struct {
+	Message string
+	Pos     Position
+}
+
+func (e *Error) Error() string {
+	if e.Pos.Line == 0 && e.Pos.Filename == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// Position implements the errorPosition interface used to sort participle.ErrorList.
+func (e *Error) Position() Position { return e.Pos }
+
+// Errorf creates a new Error at pos.
+func Errorf(pos Position, format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...), Pos: pos}
+}
+
+// ConsumeAll reads every remaining token from lex, including the final EOF token.
+func ConsumeAll(lex Lexer) ([]Token, error) {
+	var tokens []Token
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+		if token.EOF() {
+			return tokens, nil
+		}
+	}
+}
+
+// PeekingLexer is a Lexer that supports arbitrary lookahead without consuming tokens.
+type PeekingLexer interface {
+	Lexer
+	Peek(n int) (Token, error)
+}
+
+// Upgrade wraps lex so that its tokens can be peeked at before being consumed.
+func Upgrade(lex Lexer) PeekingLexer {
+	return &peekingLexer{lex: lex}
+}
+
+type peekingLexer struct {
+	lex    Lexer
+	peeked []Token
+}
+
+func (p *peekingLexer) fill(n int) error {
+	for len(p.peeked) <= n {
+		if l := len(p.peeked); l > 0 && p.peeked[l-1].EOF() {
+			return nil
+		}
+		token, err := p.lex.Next()
+		if err != nil {
+			return err
+		}
+		p.peeked = append(p.peeked, token)
+	}
+	return nil
+}
+
+func (p *peekingLexer) Peek(n int) (Token, error) {
+	if err := p.fill(n); err != nil {
+		return Token{}, err
+	}
+	if n >= len(p.peeked) {
+		return p.peeked[len(p.peeked)-1], nil
+	}
+	return p.peeked[n], nil
+}
+
+func (p *peekingLexer) Next() (Token, error) {
+	if err := p.fill(0); err != nil {
+		return Token{}, err
+	}
+	token := p.peeked[0]
+	if len(p.peeked) > 1 {
+		p.peeked = p.peeked[1:]
+	} else if !token.EOF() {
+		p.peeked = p.peeked[:0]
+	}
+	return token, nil
+}
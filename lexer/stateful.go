@@ -0,0 +1,183 @@
+package lexer
+
+import (
+	"io"
+	"io/ioutil"
+	"regexp"
+)
+
+// Action is applied to a Stateful lexer's mode stack after a Rule matches.
+//
+// Push("state") enters a new mode, Pop() returns to the previous one, and nil leaves
+// the stack unchanged.
+type Action interface {
+	apply(stack []string) []string
+}
+
+type pushAction string
+
+func (p pushAction) apply(stack []string) []string {
+	return append(stack, string(p))
+}
+
+type popAction struct{}
+
+func (popAction) apply(stack []string) []string {
+	if len(stack) == 1 {
+		return stack
+	}
+	return stack[:len(stack)-1]
+}
+
+// Push returns an Action that enters mode "state", to be returned to later with Pop().
+func Push(state string) Action { return pushAction(state) }
+
+// Pop returns an Action that leaves the current mode, returning to the previous one on
+// the stack. Popping the "Root" mode is a no-op.
+func Pop() Action { return popAction{} }
+
+// Rule is a named pattern matched against the head of the remaining input while in a
+// particular mode. Action, if non-nil, is applied to the mode stack after the rule
+// matches.
+type Rule struct {
+	Name    string
+	Pattern string
+	Action  Action
+}
+
+// Rules is a Stateful lexer definition: an ordered list of Rule per named mode. Every
+// Rules must define a "Root" mode, which is the initial state of the stack.
+type Rules map[string][]Rule
+
+type compiledRule struct {
+	Rule
+	re *regexp.Regexp
+}
+
+// StatefulDefinition is a Definition that maintains an explicit stack of modes,
+// starting at "Root", matching the leftmost Rule of the current mode against the head
+// of the remaining input and applying its Action, if any, to the stack.
+//
+// This allows grammars that embed sub-languages - string interpolation, heredocs,
+// template tags - that a single-mode lexer can't express.
+type StatefulDefinition struct {
+	rules   map[string][]compiledRule
+	symbols map[string]rune
+}
+
+// Stateful constructs a new stateful lexer from the given rules.
+func Stateful(rules Rules) (*StatefulDefinition, error) {
+	if _, ok := rules["Root"]; !ok {
+		return nil, Errorf(Position{}, "stateful lexer requires a \"Root\" mode")
+	}
+	compiled := map[string][]compiledRule{}
+	symbols := map[string]rune{"EOF": EOF}
+	rn := rune(-2)
+	for mode, set := range rules {
+		for _, rule := range set {
+			if _, ok := symbols[rule.Name]; !ok {
+				symbols[rule.Name] = rn
+				rn--
+			}
+			if push, ok := rule.Action.(pushAction); ok {
+				if _, ok := rules[string(push)]; !ok {
+					return nil, Errorf(Position{}, "stateful lexer: rule %q in mode %q pushes undefined mode %q", rule.Name, mode, string(push))
+				}
+			}
+		}
+	}
+	for mode, set := range rules {
+		compiledSet := make([]compiledRule, 0, len(set))
+		for _, rule := range set {
+			re, err := regexp.Compile("^(?:" + rule.Pattern + ")")
+			if err != nil {
+				return nil, Errorf(Position{}, "stateful lexer: invalid pattern for rule %q: %s", rule.Name, err)
+			}
+			compiledSet = append(compiledSet, compiledRule{Rule: rule, re: re})
+		}
+		compiled[mode] = compiledSet
+	}
+	return &StatefulDefinition{rules: compiled, symbols: symbols}, nil
+}
+
+// Symbols returns the token types produced by this lexer, keyed by rule name, plus
+// "EOF".
+func (d *StatefulDefinition) Symbols() map[string]rune {
+	return d.symbols
+}
+
+// Lex tokenises r according to the current mode's rules, starting in "Root".
+func (d *StatefulDefinition) Lex(r io.Reader) Lexer {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &errorLexer{err: err}
+	}
+	return &statefulLexer{
+		def:   d,
+		data:  data,
+		stack: []string{"Root"},
+		pos:   Position{Line: 1, Column: 1},
+	}
+}
+
+type errorLexer struct{ err error }
+
+func (e *errorLexer) Next() (Token, error) { return Token{}, e.err }
+
+type statefulLexer struct {
+	def   *StatefulDefinition
+	data  []byte
+	pos   Position
+	stack []string
+}
+
+// Next matches the leftmost rule of the current mode against the remaining input,
+// advances past it, applies its Action to the mode stack, and returns the matched
+// token.
+func (s *statefulLexer) Next() (Token, error) {
+	if len(s.data) == 0 {
+		return Token{Type: EOF, Pos: s.pos}, nil
+	}
+	mode := s.stack[len(s.stack)-1]
+	rules, ok := s.def.rules[mode]
+	if !ok {
+		return Token{}, Errorf(s.pos, "stateful lexer: no rules defined for mode %q", mode)
+	}
+	for _, rule := range rules {
+		loc := rule.re.FindIndex(s.data)
+		if loc == nil || loc[0] != 0 {
+			continue
+		}
+		if loc[1] == 0 {
+			return Token{}, Errorf(s.pos, "stateful lexer: rule %q in mode %q matched zero-width input; patterns must consume at least one character", rule.Name, mode)
+		}
+		match := s.data[:loc[1]]
+		token := Token{Type: s.def.symbols[rule.Name], Value: string(match), Pos: s.pos}
+		s.advance(match)
+		s.data = s.data[loc[1]:]
+		if rule.Action != nil {
+			s.stack = rule.Action.apply(s.stack)
+		}
+		return token, nil
+	}
+	return Token{}, Errorf(s.pos, "stateful lexer: no rule matched in mode %q at %q", mode, string(s.data[:min(len(s.data), 16)]))
+}
+
+func (s *statefulLexer) advance(match []byte) {
+	for _, b := range match {
+		if b == '\n' {
+			s.pos.Line++
+			s.pos.Column = 1
+		} else {
+			s.pos.Column++
+		}
+	}
+	s.pos.Offset += len(match)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
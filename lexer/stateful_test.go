@@ -0,0 +1,71 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatefulRejectsUndefinedPushTarget(t *testing.T) {
+	_, err := Stateful(Rules{
+		"Root": {
+			{Name: "Enter", Pattern: `"`, Action: Push("String")},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a Push() target with no matching mode")
+	}
+}
+
+func TestStatefulRequiresRootMode(t *testing.T) {
+	_, err := Stateful(Rules{
+		"Other": {{Name: "X", Pattern: `x`}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a definition with no Root mode")
+	}
+}
+
+func TestStatefulLexerRejectsZeroWidthMatch(t *testing.T) {
+	def, err := Stateful(Rules{
+		"Root": {
+			{Name: "Spaces", Pattern: ` *`},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building lexer: %s", err)
+	}
+	lex := def.Lex(strings.NewReader("x"))
+	if _, err := lex.Next(); err == nil {
+		t.Fatal("expected an error rather than hanging on a zero-width match")
+	}
+}
+
+func TestStatefulLexerEmitsPushedModeTokens(t *testing.T) {
+	def, err := Stateful(Rules{
+		"Root": {
+			{Name: "Quote", Pattern: `"`, Action: Push("String")},
+		},
+		"String": {
+			{Name: "Quote", Pattern: `"`, Action: Pop()},
+			{Name: "Char", Pattern: `[^"]+`},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building lexer: %s", err)
+	}
+	lex := def.Lex(strings.NewReader(`"hi"`))
+	var types []rune
+	for {
+		tok, err := lex.Next()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if tok.Type == EOF {
+			break
+		}
+		types = append(types, tok.Type)
+	}
+	if len(types) != 3 {
+		t.Fatalf("expected 3 tokens (open quote, chars, close quote), got %d", len(types))
+	}
+}
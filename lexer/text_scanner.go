@@ -0,0 +1,54 @@
+package lexer
+
+import (
+	"io"
+	"text/scanner"
+)
+
+// textScannerSymbols are the token types produced by TextScannerLexer, named after
+// the corresponding text/scanner constants.
+var textScannerSymbols = map[string]rune{
+	"EOF":       EOF,
+	"Ident":     scanner.Ident,
+	"Int":       scanner.Int,
+	"Float":     scanner.Float,
+	"Char":      scanner.Char,
+	"String":    scanner.String,
+	"RawString": scanner.RawString,
+	"Comment":   scanner.Comment,
+}
+
+// TextScannerLexer is the default Definition used by Build when no Lexer() option is
+// given. It recognises typical Go-like tokens: identifiers, int/float literals,
+// quoted strings, runes and comments, plus individual punctuation runes (each
+// returned as its own token, typed as that rune).
+var TextScannerLexer Definition = &textScannerDefinition{}
+
+type textScannerDefinition struct{}
+
+func (d *textScannerDefinition) Symbols() map[string]rune {
+	return textScannerSymbols
+}
+
+func (d *textScannerDefinition) Lex(r io.Reader) Lexer {
+	s := &scanner.Scanner{}
+	s.Init(r)
+	s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanChars |
+		scanner.ScanStrings | scanner.ScanRawStrings | scanner.ScanComments
+	s.Error = func(*scanner.Scanner, string) {} // Scan errors are discarded; Next never returns an error.
+	return &textScannerLexer{scanner: s}
+}
+
+type textScannerLexer struct {
+	scanner *scanner.Scanner
+}
+
+func (t *textScannerLexer) Next() (Token, error) {
+	r := t.scanner.Scan()
+	pos := t.scanner.Position
+	return Token{
+		Type:  r,
+		Value: t.scanner.TokenText(),
+		Pos:   Position{Filename: pos.Filename, Offset: pos.Offset, Line: pos.Line, Column: pos.Column},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package participle
+
+import (
+	"io"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// Mapper transforms a token before it reaches the grammar, eg. to rewrite identifiers
+// or case-fold keywords.
+type Mapper func(token lexer.Token) (lexer.Token, error)
+
+// identityMapper is the default Mapper: it returns every token unchanged.
+func identityMapper(token lexer.Token) (lexer.Token, error) { return token, nil }
+
+// Map adds a Mapper run over every token the lexer produces, before it reaches the
+// grammar. Map options compose: each wraps the previously configured mapper.
+func Map(mapper Mapper) Option {
+	return func(p *Parser) error {
+		prev := p.mapper
+		p.mapper = func(token lexer.Token) (lexer.Token, error) {
+			token, err := prev(token)
+			if err != nil {
+				return token, err
+			}
+			return mapper(token)
+		}
+		return nil
+	}
+}
+
+// mappingLexerDef wraps a lexer.Definition, applying a Mapper to every token it
+// produces.
+type mappingLexerDef struct {
+	lexer.Definition
+	mapper Mapper
+}
+
+func (m *mappingLexerDef) Lex(r io.Reader) lexer.Lexer {
+	return &mappingLexer{Lexer: m.Definition.Lex(r), mapper: m.mapper}
+}
+
+type mappingLexer struct {
+	lexer.Lexer
+	mapper Mapper
+}
+
+func (m *mappingLexer) Next() (lexer.Token, error) {
+	token, err := m.Lexer.Next()
+	if err != nil {
+		return token, err
+	}
+	return m.mapper(token)
+}
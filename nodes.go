@@ -0,0 +1,162 @@
+package participle
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// node is a single element of a built grammar: a struct sequence, a single-token
+// capture, a reference to another node (for `@@`), or a union alternation. Parse
+// consumes from ctx and, on a match, populates the relevant field(s) of parent -
+// which must be the addressable struct value the node's fields live on - returning a
+// non-nil slice as a signal of success. Returning (nil, nil) means "didn't match, and
+// consumed nothing", which callers earlier in a sequence may use to try an
+// alternative; returning a non-nil error means a hard failure that should not be
+// recovered from locally.
+type node interface {
+	Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error)
+}
+
+// parseContext carries per-Parse state through the node tree.
+type parseContext struct {
+	lexer.PeekingLexer
+	caseInsensitive map[rune]bool
+}
+
+// Parseable is implemented by grammar types that parse themselves directly from the
+// token stream, bypassing reflection-driven struct parsing.
+type Parseable interface {
+	Parse(lex lexer.PeekingLexer) error
+}
+
+// NextMatch is returned by a Parseable.Parse to indicate "this isn't a match" without
+// it being treated as a hard parse error.
+var NextMatch = errors.New("no match") //nolint: golint
+
+// strctNode parses each of fields in sequence into an instance of typ.
+type strctNode struct {
+	typ    reflect.Type
+	fields []node
+}
+
+func (s *strctNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	for i, field := range s.fields {
+		out, err := field.Parse(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			if i == 0 {
+				return nil, nil
+			}
+			token, peekErr := ctx.Peek(0)
+			if peekErr != nil {
+				return nil, peekErr
+			}
+			return nil, lexer.Errorf(token.Pos, "expected next field of %s but got %q", s.typ, token)
+		}
+	}
+	return []reflect.Value{parent}, nil
+}
+
+// captureNode consumes a single token of the given symbol and assigns its text to
+// fieldIndex of the parent struct.
+type captureNode struct {
+	fieldIndex []int
+	fieldName  string
+	symbol     rune
+	symbolName string
+}
+
+func (c *captureNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	token, err := ctx.Peek(0)
+	if err != nil {
+		return nil, err
+	}
+	if token.Type != c.symbol {
+		return nil, nil
+	}
+	if _, err := ctx.Next(); err != nil {
+		return nil, err
+	}
+	fv := parent.FieldByIndex(c.fieldIndex)
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(token.Value)
+	case reflect.Interface:
+		fv.Set(reflect.ValueOf(token.Value))
+	default:
+		return nil, lexer.Errorf(token.Pos, "cannot capture %s into field of kind %s", c.symbolName, fv.Kind())
+	}
+	return []reflect.Value{parent}, nil
+}
+
+// referenceNode parses target into fieldIndex of the parent struct - a nested struct,
+// pointer-to-struct, or (via a *unionNode target) an interface field.
+type referenceNode struct {
+	fieldIndex []int
+	fieldName  string
+	fieldType  reflect.Type
+	target     node
+}
+
+func (r *referenceNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	fv := parent.FieldByIndex(r.fieldIndex)
+	switch fv.Kind() {
+	case reflect.Interface:
+		out, err := r.target.Parse(ctx, parent)
+		if err != nil || out == nil {
+			return nil, err
+		}
+		fv.Set(out[0])
+		return []reflect.Value{parent}, nil
+	case reflect.Ptr:
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		out, err := r.target.Parse(ctx, fv.Elem())
+		if err != nil || out == nil {
+			return nil, err
+		}
+		return []reflect.Value{parent}, nil
+	default:
+		out, err := r.target.Parse(ctx, fv)
+		if err != nil || out == nil {
+			return nil, err
+		}
+		return []reflect.Value{parent}, nil
+	}
+}
+
+// dumpNode renders n as a single-line EBNF-ish fragment, used by Parser.String() and
+// as the fallback for node kinds describeNode doesn't have a dedicated case for.
+func dumpNode(n node) string {
+	switch n := n.(type) {
+	case *strctNode:
+		out := ""
+		for i, f := range n.fields {
+			if i > 0 {
+				out += " "
+			}
+			out += dumpNode(f)
+		}
+		return out
+	case *captureNode:
+		return "<" + n.symbolName + ">"
+	case *referenceNode:
+		return dumpNode(n.target)
+	case *unionNode:
+		out := "("
+		for i, m := range n.members {
+			if i > 0 {
+				out += " | "
+			}
+			out += dumpNode(m)
+		}
+		return out + ")"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,101 @@
+package participle
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const hostIntBits = 32 << (^uint(0) >> 63)
+
+// NumericInference, when the target of a capture is an interface{}, tries
+// narrower-to-wider numeric conversions in turn - ParseInt at the host int width, then
+// int64, then float32, then float64 - assigning the first one that succeeds. Combined
+// with an `@Number` capture, a lexer that declares a single "Number" symbol can feed
+// either integer or floating point literals without separate Int/Float alternatives in
+// the grammar; lexer.TextScannerLexer declares no "Number" symbol, so with it
+// `@Number` is a build error. It does not apply to interface fields registered with
+// Union(), which are always resolved to one of the registered struct types, never a
+// bare number; the fields of the resolved member are walked like any others.
+func NumericInference() Option {
+	return func(p *Parser) error {
+		p.numericInference = true
+		return nil
+	}
+}
+
+// inferNumber parses s as the narrowest of int, int64, float32 or float64 that fits,
+// in that order, returning the first successful conversion.
+//
+// A plain strconv.ParseFloat(s, 32) only reports magnitude overflow, not precision
+// loss, so it would accept almost any float literal and the float64 fallback would
+// never be used. Instead, float64 is always parsed first, and float32 is only chosen
+// if converting it back to float64 reproduces the same value exactly.
+func inferNumber(s string) (interface{}, error) {
+	if n, err := strconv.ParseInt(s, 10, hostIntBits); err == nil {
+		return int(n), nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if f32 := float32(f); float64(f32) == f {
+			return f32, nil
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("%q is not a valid number", s)
+}
+
+// applyNumericInference walks v's struct fields, descending into the pointer held by
+// an interface field such as a Union() member, and for any interface{} field currently
+// holding the raw captured string (what any capture into interface{} stores), replaces
+// it with the value inferNumber infers from it.
+func applyNumericInference(v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Interface && !fv.IsNil() {
+			if s, ok := fv.Interface().(string); ok {
+				n, err := inferNumber(s)
+				if err != nil {
+					return err
+				}
+				fv.Set(reflect.ValueOf(n))
+				continue
+			}
+		}
+		switch fv.Kind() {
+		case reflect.Struct, reflect.Ptr:
+			if err := applyNumericInference(fv); err != nil {
+				return err
+			}
+		case reflect.Interface:
+			if !fv.IsNil() && fv.Elem().Kind() == reflect.Ptr {
+				if err := applyNumericInference(fv.Elem()); err != nil {
+					return err
+				}
+			}
+		case reflect.Slice:
+			for j := 0; j < fv.Len(); j++ {
+				if err := applyNumericInference(fv.Index(j)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package participle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferNumberPrefersInt(t *testing.T) {
+	v, err := inferNumber("42")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := v.(int); !ok {
+		t.Fatalf("expected int, got %T", v)
+	}
+}
+
+func TestInferNumberFallsBackToInt64(t *testing.T) {
+	// Larger than the widest 32-bit int, but not a float.
+	v, err := inferNumber("9999999999")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := v.(int64); !ok {
+		if hostIntBits == 64 {
+			return // on a 64-bit host this fits in int, which is also fine.
+		}
+		t.Fatalf("expected int64, got %T", v)
+	}
+}
+
+func TestInferNumberUsesFloat32WhenExact(t *testing.T) {
+	v, err := inferNumber("1.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := v.(float32); !ok {
+		t.Fatalf("expected float32 for an exactly-representable literal, got %T", v)
+	}
+}
+
+func TestInferNumberFallsBackToFloat64WhenPrecisionWouldBeLost(t *testing.T) {
+	// This value is not exactly representable as a float32.
+	const s = "0.123456789012345"
+	v, err := inferNumber(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f, ok := v.(float64)
+	if !ok {
+		t.Fatalf("expected float64 to preserve precision, got %T", v)
+	}
+	if float64(float32(f)) == f {
+		t.Fatalf("test literal %q round-trips through float32 exactly; pick a different literal", s)
+	}
+}
+
+func TestInferNumberRejectsNonNumeric(t *testing.T) {
+	if _, err := inferNumber("not-a-number"); err == nil {
+		t.Fatal("expected an error for a non-numeric string")
+	}
+}
+
+type numericTestNode struct {
+	Value interface{}
+}
+
+func TestApplyNumericInferenceConvertsStringField(t *testing.T) {
+	v := &numericTestNode{Value: "3.25"}
+	if err := applyNumericInference(reflect.ValueOf(v)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := v.Value.(float32); !ok {
+		t.Fatalf("expected Value to be converted to float32, got %T (%v)", v.Value, v.Value)
+	}
+}
+
+// TestApplyNumericInferenceLeavesUnionFieldsAlone documents and verifies the
+// NumericInference()/Union() boundary: a Union()-resolved interface field already
+// holds a concrete *struct (not a raw captured string), so applyNumericInference's
+// string-in-interface check never fires for it and the value passes through
+// unchanged.
+func TestApplyNumericInferenceLeavesUnionFieldsAlone(t *testing.T) {
+	w := &unionTestWrapper{Expr: &unionTestLit{Value: "3"}}
+	if err := applyNumericInference(reflect.ValueOf(w)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lit, ok := w.Expr.(*unionTestLit)
+	if !ok {
+		t.Fatalf("expected Expr to remain a *unionTestLit, got %T", w.Expr)
+	}
+	if lit.Value != "3" {
+		t.Fatalf("expected Value to be left as the string %q, got %q", "3", lit.Value)
+	}
+}
@@ -0,0 +1,35 @@
+package participle
+
+import "github.com/alecthomas/participle/lexer"
+
+// Option configures a Parser at Build() time.
+type Option func(*Parser) error
+
+// Lexer sets the lexer.Definition used to tokenise input. The default, if this option
+// isn't given, is lexer.TextScannerLexer.
+func Lexer(def lexer.Definition) Option {
+	return func(p *Parser) error {
+		p.lex = def
+		return nil
+	}
+}
+
+// CaseInsensitive records a lexer symbol as case-insensitive. Parse passes the set to
+// the grammar's nodes, but none of them consults it: tokens are matched by symbol type
+// alone.
+func CaseInsensitive(symbol string) Option {
+	return func(p *Parser) error {
+		p.caseInsensitive[symbol] = true
+		return nil
+	}
+}
+
+// UseLookahead sets the parser's lookahead flag, which makes Build pass the grammar
+// through applyLookahead. applyLookahead leaves the grammar unchanged, so alternatives
+// are still chosen by trying each one in order.
+func UseLookahead() Option {
+	return func(p *Parser) error {
+		p.useLookahead = true
+		return nil
+	}
+}
@@ -13,12 +13,17 @@ import (
 
 // A Parser for a particular grammar and lexer.
 type Parser struct {
-	root            node
-	lex             lexer.Definition
-	typ             reflect.Type
-	mapper          Mapper
-	useLookahead    bool
-	caseInsensitive map[string]bool
+	root             node
+	lex              lexer.Definition
+	typ              reflect.Type
+	mapper           Mapper
+	useLookahead     bool
+	caseInsensitive  map[string]bool
+	unions           map[reflect.Type][]reflect.Type
+	recoverMax       int
+	syncTokens       []string
+	commentSymbol    string
+	numericInference bool
 }
 
 // MustBuild calls Build(grammar, options...) and panics if an error occurs.
@@ -56,9 +61,18 @@ func Build(grammar interface{}, options ...Option) (parser *Parser, err error) {
 	if p.mapper != nil {
 		p.lex = &mappingLexerDef{p.lex, p.mapper}
 	}
+	if p.commentSymbol != "" {
+		p.lex = &commentLexerDef{p.lex, p.commentSymbol}
+	}
 
 	context := newGeneratorContext(p.lex)
 	p.typ = reflect.TypeOf(grammar)
+	if err = validateUnions(p.typ, p.unions, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	if context.unions, err = buildUnionNodes(context, p.unions); err != nil {
+		return nil, err
+	}
 	p.root, err = context.parseType(p.typ)
 	if err != nil {
 		return nil, err
@@ -82,7 +96,9 @@ func (p *Parser) Parse(r io.Reader, v interface{}) (err error) {
 	if reflect.TypeOf(v) != p.typ {
 		return fmt.Errorf("must parse into value of type %s not %T", p.typ, v)
 	}
-	lex := lexer.Upgrade(p.lex.Lex(r))
+	rawLex := p.lex.Lex(r)
+	commentLex, _ := rawLex.(*commentLexer)
+	lex := lexer.Upgrade(rawLex)
 	caseInsensitive := map[rune]bool{}
 	for sym, rn := range p.lex.Symbols() {
 		if p.caseInsensitive[sym] {
@@ -105,6 +121,14 @@ func (p *Parser) Parse(r io.Reader, v interface{}) (err error) {
 	if len(pv) > 0 {
 		rv.Elem().Set(reflect.Indirect(pv[0]))
 	}
+	if commentLex != nil {
+		attachDocComments(rv.Elem(), commentLex)
+	}
+	if p.numericInference {
+		if err = applyNumericInference(rv.Elem()); err != nil {
+			return err
+		}
+	}
 	token, err := lex.Peek(0)
 	if err != nil {
 		return err
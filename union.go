@@ -0,0 +1,154 @@
+package participle
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// Union registers the ordered set of concrete grammar types that may satisfy an
+// interface-typed field or grammar root. iface must be a nil pointer to the interface,
+// eg. (*Expr)(nil), and each of members must be a struct (or pointer to struct) that
+// implements it.
+//
+// At parse time, a field of type iface tries each member in registration order and is
+// assigned the first one that matches; a member that fails after consuming tokens ends
+// the parse with its error instead of falling through to the next member. Build also
+// accepts a grammar root of type iface, but Parse only parses into a pointer to a
+// struct, so such a root cannot be parsed. Build returns an error if the grammar
+// references an interface type that was not registered via Union.
+func Union(iface interface{}, members ...interface{}) Option {
+	return func(p *Parser) error {
+		ifaceType := reflect.TypeOf(iface)
+		if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+			return fmt.Errorf("participle: Union() iface argument must be a nil pointer to an interface, eg. (*Expr)(nil)")
+		}
+		ifaceType = ifaceType.Elem()
+		if len(members) == 0 {
+			return fmt.Errorf("participle: Union(%s) requires at least one member", ifaceType)
+		}
+		memberTypes := make([]reflect.Type, 0, len(members))
+		for _, member := range members {
+			t := reflect.TypeOf(member)
+			for t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t == nil || t.Kind() != reflect.Struct {
+				return fmt.Errorf("participle: Union(%s) member %T must be a struct", ifaceType, member)
+			}
+			if !reflect.PtrTo(t).Implements(ifaceType) {
+				return fmt.Errorf("participle: Union(%s) member %s does not implement %s", ifaceType, t, ifaceType)
+			}
+			memberTypes = append(memberTypes, t)
+		}
+		if p.unions == nil {
+			p.unions = map[reflect.Type][]reflect.Type{}
+		}
+		p.unions[ifaceType] = memberTypes
+		return nil
+	}
+}
+
+// unionNode is the node for an interface-typed field or root, trying each registered
+// member in order and assigning the field to the first one that matches. Each member
+// is parsed into a freshly allocated instance of its concrete type (memberTypes[i]),
+// since unlike a plain struct field the enclosing parent has nowhere to hold a member
+// in place - the interface field is set to a pointer to whichever instance matched.
+type unionNode struct {
+	iface       reflect.Type
+	members     []node
+	memberTypes []reflect.Type
+}
+
+func (u *unionNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	for i, member := range u.members {
+		inst := reflect.New(u.memberTypes[i]).Elem()
+		v, err := member.Parse(ctx, inst)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			return []reflect.Value{inst.Addr()}, nil
+		}
+	}
+	token, err := ctx.Peek(0)
+	if err != nil {
+		return nil, err
+	}
+	return nil, lexer.Errorf(token.Pos, "no alternative of union %s matched %q", u.iface, token)
+}
+
+func (u *unionNode) String() string {
+	out := make([]string, 0, len(u.members))
+	for _, m := range u.members {
+		out = append(out, dumpNode(m))
+	}
+	return "(" + joinWithPipe(out) + ")"
+}
+
+func joinWithPipe(parts []string) string {
+	s := ""
+	for i, p := range parts {
+		if i > 0 {
+			s += " | "
+		}
+		s += p
+	}
+	return s
+}
+
+// buildUnionNodes constructs a *unionNode for every interface registered via Union,
+// by running each member type through context.parseType the same way any other
+// grammar type is built. The result is stored on context so that parseType's
+// interface-kind case can look up the prebuilt alternation by interface type instead
+// of building it inline.
+func buildUnionNodes(context *generatorContext, unions map[reflect.Type][]reflect.Type) (map[reflect.Type]*unionNode, error) {
+	if len(unions) == 0 {
+		return nil, nil
+	}
+	out := make(map[reflect.Type]*unionNode, len(unions))
+	for iface, members := range unions {
+		u := &unionNode{iface: iface}
+		for _, member := range members {
+			memberNode, err := context.parseType(member)
+			if err != nil {
+				return nil, fmt.Errorf("participle: building union %s: %w", iface, err)
+			}
+			u.members = append(u.members, memberNode)
+			u.memberTypes = append(u.memberTypes, member)
+		}
+		out[iface] = u
+	}
+	return out, nil
+}
+
+// validateUnions checks typ and the field types it references with "@@" (recursing
+// into structs, pointers, slices and arrays), and returns an error if any of them is an
+// interface that was not registered via Union. Fields captured with "@Symbol" may have
+// interface type, eg. interface{} for NumericInference().
+func validateUnions(typ reflect.Type, unions map[reflect.Type][]reflect.Type, seen map[reflect.Type]bool) error {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Interface {
+		if _, ok := unions[typ]; !ok {
+			return fmt.Errorf("participle: grammar references interface %s but it was not registered with Union()", typ)
+		}
+		return nil
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("parser") != "@@" {
+			continue
+		}
+		if err := validateUnions(field.Type, unions, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
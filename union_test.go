@@ -0,0 +1,96 @@
+package participle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+type unionTestExpr interface{ exprNode() }
+
+type unionTestLit struct {
+	Value string `parser:"@Ident"`
+}
+
+func (*unionTestLit) exprNode() {}
+
+type unionTestNotAnExpr struct {
+	Value string `parser:"@Ident"`
+}
+
+func TestUnionRequiresInterfacePointer(t *testing.T) {
+	err := Union((*unionTestLit)(nil), &unionTestLit{})(&Parser{})
+	if err == nil {
+		t.Fatal("expected error for non-interface argument")
+	}
+}
+
+func TestUnionRequiresAtLeastOneMember(t *testing.T) {
+	err := Union((*unionTestExpr)(nil))(&Parser{})
+	if err == nil {
+		t.Fatal("expected error for zero members")
+	}
+}
+
+func TestUnionMemberMustImplementInterface(t *testing.T) {
+	err := Union((*unionTestExpr)(nil), &unionTestNotAnExpr{})(&Parser{})
+	if err == nil {
+		t.Fatal("expected error when member does not implement interface")
+	}
+}
+
+func TestUnionRegistersMembers(t *testing.T) {
+	p := &Parser{}
+	if err := Union((*unionTestExpr)(nil), &unionTestLit{})(p); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ifaceType := reflect.TypeOf((*unionTestExpr)(nil)).Elem()
+	members, ok := p.unions[ifaceType]
+	if !ok || len(members) != 1 || members[0] != reflect.TypeOf(unionTestLit{}) {
+		t.Fatalf("expected union to register unionTestLit, got %v", members)
+	}
+}
+
+// failNode is a node that never matches, used to exercise unionNode's no-match path.
+type failNode struct{}
+
+func (failNode) Parse(ctx parseContext, parent reflect.Value) ([]reflect.Value, error) {
+	return nil, nil
+}
+
+func TestUnionNodeReturnsHelpfulErrorOnNoMatch(t *testing.T) {
+	lex := lexer.Upgrade(lexer.TextScannerLexer.Lex(strings.NewReader("???")))
+	u := &unionNode{
+		iface:       reflect.TypeOf((*unionTestExpr)(nil)).Elem(),
+		members:     []node{failNode{}, failNode{}},
+		memberTypes: []reflect.Type{reflect.TypeOf(unionTestLit{}), reflect.TypeOf(unionTestLit{})},
+	}
+	_, err := u.Parse(parseContext{PeekingLexer: lex}, reflect.Value{})
+	if err == nil {
+		t.Fatal("expected an error when no union member matches")
+	}
+}
+
+type unionTestWrapper struct {
+	Expr unionTestExpr `parser:"@@"`
+}
+
+func TestUnionEndToEndBuildAndParse(t *testing.T) {
+	parser, err := Build(&unionTestWrapper{}, Union((*unionTestExpr)(nil), &unionTestLit{}))
+	if err != nil {
+		t.Fatalf("Build: %s", err)
+	}
+	w := &unionTestWrapper{}
+	if err := parser.ParseString("hello", w); err != nil {
+		t.Fatalf("ParseString: %s", err)
+	}
+	lit, ok := w.Expr.(*unionTestLit)
+	if !ok {
+		t.Fatalf("expected *unionTestLit, got %T", w.Expr)
+	}
+	if lit.Value != "hello" {
+		t.Fatalf("expected Value %q, got %q", "hello", lit.Value)
+	}
+}